@@ -0,0 +1,135 @@
+package csmap
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// AtomicCSMap is a sharded concurrent hashmap whose reads never take a lock. Each shard
+// holds an atomic.Pointer to an immutable map; Get loads the pointer and reads the map
+// directly. Writers take the shard's mutex, clone the current map with the mutation
+// applied, and swap in the new pointer (copy-on-write). This trades write cost (an
+// O(shard size) clone per write) for lock-free reads, which is a good trade for
+// read-heavy workloads and a bad one for write-heavy ones; see BenchmarkAtomicCSMapGet
+// and BenchmarkAtomicCSMapSet for the difference against the RWMutex-based CSMap.
+type AtomicCSMap[K comparable, V any] struct {
+	shards []*atomicShard[K, V]
+	length int // Number of shards, always a power of two
+	mask   uint64
+	hasher func(K) uint64
+}
+
+// atomicShard is a single copy-on-write shard of an AtomicCSMap.
+type atomicShard[K comparable, V any] struct {
+	mu sync.Mutex // Serializes writers; never taken by readers
+	m  atomic.Pointer[map[K]V]
+}
+
+// NewAtomicCSMap creates a new AtomicCSMap with the specified number of shards, using
+// the same default hasher as NewCSMap.
+func NewAtomicCSMap[K comparable, V any](length int) *AtomicCSMap[K, V] {
+	return NewAtomicCSMapWithHasher[K, V](length, defaultHasher[K])
+}
+
+// NewAtomicCSMapWithHasher creates a new AtomicCSMap with the specified number of
+// shards, using hasher to pick the shard for a key instead of the default hasher.
+func NewAtomicCSMapWithHasher[K comparable, V any](length int, hasher func(K) uint64) *AtomicCSMap[K, V] {
+	if hasher == nil {
+		hasher = defaultHasher[K]
+	}
+	if length < 1 {
+		length = 1
+	}
+	length = nextPowerOfTwo(length)
+	shards := make([]*atomicShard[K, V], length)
+	for i := 0; i < length; i++ {
+		s := &atomicShard[K, V]{}
+		empty := make(map[K]V)
+		s.m.Store(&empty)
+		shards[i] = s
+	}
+	return &AtomicCSMap[K, V]{
+		shards: shards,
+		length: length,
+		mask:   uint64(length - 1),
+		hasher: hasher,
+	}
+}
+
+// Get retrieves the value associated with the given key without taking any lock.
+func (c *AtomicCSMap[K, V]) Get(key K) (V, bool) {
+	s := c.getShard(key)
+	m := s.m.Load()
+	v, ok := (*m)[key]
+	return v, ok
+}
+
+// Set adds or updates the value for a given key, cloning the shard's map under the
+// shard's mutex and atomically publishing the clone.
+func (c *AtomicCSMap[K, V]) Set(key K, value V) {
+	s := c.getShard(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	old := s.m.Load()
+	clone := make(map[K]V, len(*old)+1)
+	for k, v := range *old {
+		clone[k] = v
+	}
+	clone[key] = value
+	s.m.Store(&clone)
+}
+
+// Delete removes the key and its associated value, cloning the shard's map under the
+// shard's mutex and atomically publishing the clone.
+func (c *AtomicCSMap[K, V]) Delete(key K) {
+	s := c.getShard(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	old := s.m.Load()
+	if _, ok := (*old)[key]; !ok {
+		return
+	}
+	clone := make(map[K]V, len(*old))
+	for k, v := range *old {
+		if k == key {
+			continue
+		}
+		clone[k] = v
+	}
+	s.m.Store(&clone)
+}
+
+// SetMany writes every pair in pairs, grouping them by destination shard so that each
+// affected shard is cloned at most once rather than once per pair.
+func (c *AtomicCSMap[K, V]) SetMany(pairs map[K]V) {
+	byShard := make(map[int]map[K]V)
+	for k, v := range pairs {
+		idx := int(c.hasher(k) & c.mask)
+		group := byShard[idx]
+		if group == nil {
+			group = make(map[K]V)
+			byShard[idx] = group
+		}
+		group[k] = v
+	}
+
+	for idx, group := range byShard {
+		s := c.shards[idx]
+		s.mu.Lock()
+		old := s.m.Load()
+		clone := make(map[K]V, len(*old)+len(group))
+		for k, v := range *old {
+			clone[k] = v
+		}
+		for k, v := range group {
+			clone[k] = v
+		}
+		s.m.Store(&clone)
+		s.mu.Unlock()
+	}
+}
+
+// getShard returns the shard that should contain the given key.
+func (c *AtomicCSMap[K, V]) getShard(key K) *atomicShard[K, V] {
+	return c.shards[c.hasher(key)&c.mask]
+}
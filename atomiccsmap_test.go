@@ -0,0 +1,120 @@
+package csmap
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestAtomicCSMap_SetAndGet(t *testing.T) {
+	csMap := NewAtomicCSMap[string, int](10)
+
+	csMap.Set("key1", 1)
+	csMap.Set("key2", 2)
+
+	value, ok := csMap.Get("key1")
+	if !ok || value != 1 {
+		t.Errorf("Expected value 1 for key 'key1', got %v, ok: %v", value, ok)
+	}
+
+	value, ok = csMap.Get("key2")
+	if !ok || value != 2 {
+		t.Errorf("Expected value 2 for key 'key2', got %v, ok: %v", value, ok)
+	}
+
+	value, ok = csMap.Get("key3")
+	if ok {
+		t.Errorf("Expected key 'key3' to not exist, but it does with value %v", value)
+	}
+}
+
+func TestAtomicCSMap_Delete(t *testing.T) {
+	csMap := NewAtomicCSMap[string, int](10)
+
+	csMap.Set("key1", 1)
+	csMap.Delete("key1")
+
+	_, ok := csMap.Get("key1")
+	if ok {
+		t.Errorf("Expected key 'key1' to be deleted, but it still exists")
+	}
+
+	// Deleting a key that is no longer present must be a no-op, not a panic.
+	csMap.Delete("key1")
+}
+
+func TestAtomicCSMap_SetMany(t *testing.T) {
+	csMap := NewAtomicCSMap[string, int](10)
+	csMap.Set("key1", 1)
+
+	csMap.SetMany(map[string]int{
+		"key1": 10,
+		"key2": 2,
+		"key3": 3,
+	})
+
+	for key, want := range map[string]int{"key1": 10, "key2": 2, "key3": 3} {
+		value, ok := csMap.Get(key)
+		if !ok || value != want {
+			t.Errorf("Expected value %v for %q, got %v, ok: %v", want, key, value, ok)
+		}
+	}
+}
+
+func TestAtomicCSMap_ConcurrentAccess(t *testing.T) {
+	csMap := NewAtomicCSMap[int, string](10)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			csMap.Set(i, "value")
+		}(i)
+	}
+
+	wg.Wait()
+
+	for i := 0; i < 100; i++ {
+		value, ok := csMap.Get(i)
+		if !ok || value != "value" {
+			t.Errorf("Expected value 'value' for key %d, got %v, ok: %v", i, value, ok)
+		}
+	}
+}
+
+// BenchmarkAtomicCSMapSet is directly comparable to BenchmarkCSMapSet in
+// csmap_test.go: same shard count, same key space, same shape. Unlike CSMap.Set (one
+// map write under a shard mutex), AtomicCSMap.Set clones the whole shard map under the
+// shard mutex before publishing it, so its cost scales with shard size instead of being
+// O(1); expect it to lose to BenchmarkCSMapSet, and by a growing margin as shards fill
+// up. Run both together to compare on a given machine:
+//
+//	go test -bench '^(BenchmarkCSMapSet|BenchmarkAtomicCSMapSet)$' -benchmem ./...
+func BenchmarkAtomicCSMapSet(b *testing.B) {
+	csm := NewAtomicCSMap[int, int](32)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		csm.Set(i, i)
+	}
+	b.ReportAllocs()
+}
+
+// BenchmarkAtomicCSMapGet is directly comparable to BenchmarkCSMapGet in
+// csmap_test.go: same shard count, same 1000-entry population, same access pattern.
+// Unlike CSMap.Get (an RLock/RUnlock around the map read), AtomicCSMap.Get only loads an
+// atomic.Pointer and reads the resulting map directly, with no lock of any kind; expect
+// it to win on time/op. Neither Get allocates for its synchronization, so allocations
+// should match. Run both together to compare on a given machine:
+//
+//	go test -bench '^(BenchmarkCSMapGet|BenchmarkAtomicCSMapGet)$' -benchmem ./...
+func BenchmarkAtomicCSMapGet(b *testing.B) {
+	csm := NewAtomicCSMap[int, int](32)
+	for i := 0; i < 1000; i++ {
+		csm.Set(i, i)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		csm.Get(i % 1000)
+	}
+	b.ReportAllocs()
+}
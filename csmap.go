@@ -8,18 +8,57 @@
 package csmap
 
 import (
+	"fmt"
+	"math"
+	"reflect"
 	"sync"
-	"unsafe"
 )
 
 // CSMap is a concurrent hashmap structure that holds shards for thread-safe access.
 type CSMap[K comparable, V any] struct {
-	shards []*Shard[K, V] // Array of shards that partition the map
-	length int            // Number of shards
+	shards []*Shard[K, V]    // Array of shards that partition the map
+	length int               // Number of shards, always a power of two
+	mask   uint64            // length-1, used to turn a hash into a shard index
+	hasher func(K) uint64    // Hash function used to pick a shard for a key
+	eq     func(a, b V) bool // Equality function used by the compare-and-* operations
 }
 
-// NewCSMap creates a new CSMap with the specified number of shards.
+// NewCSMap creates a new CSMap with the specified number of shards, using a default
+// hasher that is correct for any comparable key type (see defaultHasher).
 func NewCSMap[K comparable, V any](length int) *CSMap[K, V] {
+	return newCSMap[K, V](length, defaultHasher[K], defaultEq[V])
+}
+
+// NewCSMapWithEq creates a new CSMap with the specified number of shards, using eq to
+// compare values in CompareAndSwap and CompareAndDelete. This is required because V is
+// declared as `any` rather than `comparable`. If eq is nil, reflect.DeepEqual is used.
+func NewCSMapWithEq[K comparable, V any](length int, eq func(a, b V) bool) *CSMap[K, V] {
+	return newCSMap[K, V](length, defaultHasher[K], eq)
+}
+
+// NewCSMapWithHasher creates a new CSMap with the specified number of shards, using
+// hasher to pick the shard for a key instead of the default hasher. This is useful when
+// the default's `fmt.Sprintf`-based fallback is too slow or when callers need keys with
+// equal contents to reliably land on the same shard for a type the default doesn't
+// special-case.
+func NewCSMapWithHasher[K comparable, V any](length int, hasher func(K) uint64) *CSMap[K, V] {
+	if hasher == nil {
+		hasher = defaultHasher[K]
+	}
+	return newCSMap[K, V](length, hasher, defaultEq[V])
+}
+
+// newCSMap builds a CSMap with the given shard count, hasher and equality function. The
+// shard count is rounded up to the next power of two so that the hash-to-shard mapping
+// can use a bitmask instead of a modulo.
+func newCSMap[K comparable, V any](length int, hasher func(K) uint64, eq func(a, b V) bool) *CSMap[K, V] {
+	if length < 1 {
+		length = 1
+	}
+	length = nextPowerOfTwo(length)
+	if eq == nil {
+		eq = defaultEq[V]
+	}
 	shards := make([]*Shard[K, V], length)
 	for i := 0; i < length; i++ {
 		shards[i] = &Shard[K, V]{
@@ -29,7 +68,108 @@ func NewCSMap[K comparable, V any](length int) *CSMap[K, V] {
 	return &CSMap[K, V]{
 		shards: shards,
 		length: length,
+		mask:   uint64(length - 1),
+		hasher: hasher,
+		eq:     eq,
+	}
+}
+
+// nextPowerOfTwo returns the smallest power of two greater than or equal to n.
+func nextPowerOfTwo(n int) int {
+	p := 1
+	for p < n {
+		p <<= 1
+	}
+	return p
+}
+
+// defaultEq is the fallback equality function installed when no eq is supplied.
+func defaultEq[V any](a, b V) bool {
+	return reflect.DeepEqual(a, b)
+}
+
+// defaultHasher is the hasher installed by NewCSMap and NewCSMapWithEq. It takes fast
+// paths for strings, the built-in integer and floating-point types (+0 and -0 hash
+// alike), pointers (hashed by address), and fixed-size byte-array keys (e.g. [16]byte
+// UUIDs), falling back to an FNV-1a hash of the key's fmt.Sprintf("%v", key)
+// representation for everything else. This avoids the correctness bug in reading a
+// key's raw bits through an unsafe.Pointer: for strings that reads the backing array
+// pointer (different for equal strings with different backing arrays), and for anything
+// wider than a word it silently truncates.
+func defaultHasher[K comparable](key K) uint64 {
+	switch k := any(key).(type) {
+	case string:
+		return fnv1a64(k)
+	case int:
+		return uint64(k)
+	case int8:
+		return uint64(k)
+	case int16:
+		return uint64(k)
+	case int32:
+		return uint64(k)
+	case int64:
+		return uint64(k)
+	case uint:
+		return uint64(k)
+	case uint8:
+		return uint64(k)
+	case uint16:
+		return uint64(k)
+	case uint32:
+		return uint64(k)
+	case uint64:
+		return k
+	case uintptr:
+		return uint64(k)
+	case float32:
+		if k == 0 {
+			return 0 // +0 and -0 are equal keys
+		}
+		return uint64(math.Float32bits(k))
+	case float64:
+		if k == 0 {
+			return 0
+		}
+		return math.Float64bits(k)
+	default:
+		if reflect.ValueOf(k).Kind() == reflect.Pointer {
+			return fnv1a64(fmt.Sprintf("%p", k))
+		}
+		if buf, ok := byteArrayBytes(k); ok {
+			return fnv1a64(string(buf))
+		}
+		return fnv1a64(fmt.Sprintf("%v", k))
+	}
+}
+
+// byteArrayBytes reports whether k is a fixed-size byte array (e.g. [16]byte) and, if
+// so, returns its contents as a []byte so the caller can hash them directly instead of
+// falling back to fmt.Sprintf.
+func byteArrayBytes(k any) ([]byte, bool) {
+	v := reflect.ValueOf(k)
+	if v.Kind() != reflect.Array || v.Type().Elem().Kind() != reflect.Uint8 {
+		return nil, false
+	}
+	buf := make([]byte, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		buf[i] = byte(v.Index(i).Uint())
+	}
+	return buf, true
+}
+
+// fnv1a64 computes the 64-bit FNV-1a hash of s.
+func fnv1a64(s string) uint64 {
+	const (
+		offset64 = 14695981039346656037
+		prime64  = 1099511628211
+	)
+	h := uint64(offset64)
+	for i := 0; i < len(s); i++ {
+		h ^= uint64(s[i])
+		h *= prime64
 	}
+	return h
 }
 
 // Set adds or updates the value for a given key in the map.
@@ -58,15 +198,164 @@ func (c *CSMap[K, V]) Delete(key K) {
 	delete(s.m, key)
 }
 
-// getShard returns the shard that should contain the given key.
-func (c *CSMap[K, V]) getShard(key K) *Shard[K, V] {
-	return c.shards[c.hash(key)] // Retrieve the appropriate shard based on the hash of the key
+// LoadOrStore returns the existing value for key if present. Otherwise, it stores and
+// returns the given value. The loaded result is true if the value was already present.
+func (c *CSMap[K, V]) LoadOrStore(key K, value V) (actual V, loaded bool) {
+	s := c.getShard(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if v, ok := s.m[key]; ok {
+		return v, true
+	}
+	s.m[key] = value
+	return value, false
 }
 
-// hash computes a hash for the key to determine which shard it belongs to.
-func (c *CSMap[K, V]) hash(key K) uintptr {
-	// Use unsafe pointer conversion to get an uintptr representation of the key
-	return *(*uintptr)(unsafe.Pointer(&key)) % uintptr(c.length)
+// LoadAndDelete deletes the value for a key, returning the previous value if any.
+// The loaded result reports whether the key was present.
+func (c *CSMap[K, V]) LoadAndDelete(key K) (V, bool) {
+	s := c.getShard(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.m[key]
+	if ok {
+		delete(s.m, key)
+	}
+	return v, ok
+}
+
+// Swap stores value for key and returns the previous value if any. The loaded result
+// reports whether the key was present.
+func (c *CSMap[K, V]) Swap(key K, value V) (previous V, loaded bool) {
+	s := c.getShard(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	previous, loaded = s.m[key]
+	s.m[key] = value
+	return previous, loaded
+}
+
+// CompareAndSwap stores new for key if the existing value equals old, as reported by the
+// map's equality function (see NewCSMapWithEq). It returns whether the swap happened.
+func (c *CSMap[K, V]) CompareAndSwap(key K, old, newValue V) bool {
+	s := c.getShard(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	current, ok := s.m[key]
+	if !ok || !c.eq(current, old) {
+		return false
+	}
+	s.m[key] = newValue
+	return true
+}
+
+// CompareAndDelete deletes the entry for key if its value equals old, as reported by the
+// map's equality function (see NewCSMapWithEq). It returns whether the delete happened.
+func (c *CSMap[K, V]) CompareAndDelete(key K, old V) bool {
+	s := c.getShard(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	current, ok := s.m[key]
+	if !ok || !c.eq(current, old) {
+		return false
+	}
+	delete(s.m, key)
+	return true
+}
+
+// Len returns the number of entries in the map, summed across shards. Each shard is
+// counted under its own RLock, so the result can be stale by the time it is returned if
+// the map is being mutated concurrently.
+func (c *CSMap[K, V]) Len() int {
+	total := 0
+	for _, s := range c.shards {
+		s.mu.RLock()
+		total += len(s.m)
+		s.mu.RUnlock()
+	}
+	return total
+}
+
+// Keys returns a snapshot of all keys currently in the map. See Range for the
+// consistency guarantees of the underlying iteration.
+func (c *CSMap[K, V]) Keys() []K {
+	keys := make([]K, 0, c.Len())
+	c.Range(func(k K, _ V) bool {
+		keys = append(keys, k)
+		return true
+	})
+	return keys
+}
+
+// Items returns a snapshot of the map's contents as a new plain Go map. Values are
+// copied by assignment, so a V that is a pointer, slice or map still shares its
+// underlying data with the CSMap. See Range for the consistency guarantees of the
+// underlying iteration.
+func (c *CSMap[K, V]) Items() map[K]V {
+	items := make(map[K]V, c.Len())
+	c.Range(func(k K, v V) bool {
+		items[k] = v
+		return true
+	})
+	return items
+}
+
+// keyValue pairs a key and value copied out of a shard for iteration.
+type keyValue[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+// Range calls fn for each key/value pair in the map, stopping early if fn returns false.
+// Each shard is locked, its entries copied to a local slice, unlocked, and only then
+// yielded to fn - so fn is free to call Set, Delete, or any other method on the map,
+// including on keys belonging to the shard currently being iterated, without
+// deadlocking. The order in which shards (and entries within a shard) are visited is
+// unspecified, and a concurrent mutation may or may not be reflected in the results fn
+// sees.
+func (c *CSMap[K, V]) Range(fn func(key K, value V) bool) {
+	for _, s := range c.shards {
+		s.mu.RLock()
+		entries := make([]keyValue[K, V], 0, len(s.m))
+		for k, v := range s.m {
+			entries = append(entries, keyValue[K, V]{key: k, value: v})
+		}
+		s.mu.RUnlock()
+
+		for _, e := range entries {
+			if !fn(e.key, e.value) {
+				return
+			}
+		}
+	}
+}
+
+// Clear removes all entries from the map, one shard at a time.
+func (c *CSMap[K, V]) Clear() {
+	for _, s := range c.shards {
+		s.mu.Lock()
+		s.m = make(map[K]V)
+		s.mu.Unlock()
+	}
+}
+
+// Upsert applies fn to the current value for key (if any) and stores the result, all
+// under a single shard lock acquisition. fn receives whether the key already existed
+// and, if so, its old value; it returns the value to store. Upsert returns the value
+// that was stored.
+func (c *CSMap[K, V]) Upsert(key K, fn func(exists bool, old V) V) V {
+	s := c.getShard(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	old, exists := s.m[key]
+	newValue := fn(exists, old)
+	s.m[key] = newValue
+	return newValue
+}
+
+// getShard returns the shard that should contain the given key.
+func (c *CSMap[K, V]) getShard(key K) *Shard[K, V] {
+	return c.shards[c.hasher(key)&c.mask] // Mask the hash since length is a power of two
 }
 
 // Shard is a structure that holds a portion of the map and provides synchronization.
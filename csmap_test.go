@@ -78,6 +78,303 @@ func TestCSMap_HashCollision(t *testing.T) {
 	}
 }
 
+func TestCSMap_LoadOrStore(t *testing.T) {
+	csMap := NewCSMap[string, int](10)
+
+	actual, loaded := csMap.LoadOrStore("key1", 1)
+	if loaded || actual != 1 {
+		t.Errorf("Expected (1, false) for first LoadOrStore, got (%v, %v)", actual, loaded)
+	}
+
+	actual, loaded = csMap.LoadOrStore("key1", 2)
+	if !loaded || actual != 1 {
+		t.Errorf("Expected (1, true) for second LoadOrStore, got (%v, %v)", actual, loaded)
+	}
+}
+
+func TestCSMap_LoadAndDelete(t *testing.T) {
+	csMap := NewCSMap[string, int](10)
+	csMap.Set("key1", 1)
+
+	value, ok := csMap.LoadAndDelete("key1")
+	if !ok || value != 1 {
+		t.Errorf("Expected (1, true), got (%v, %v)", value, ok)
+	}
+
+	if _, ok := csMap.Get("key1"); ok {
+		t.Errorf("Expected 'key1' to be deleted after LoadAndDelete")
+	}
+
+	if _, ok := csMap.LoadAndDelete("key1"); ok {
+		t.Errorf("Expected LoadAndDelete on missing key to report loaded=false")
+	}
+}
+
+func TestCSMap_Swap(t *testing.T) {
+	csMap := NewCSMap[string, int](10)
+
+	previous, loaded := csMap.Swap("key1", 1)
+	if loaded || previous != 0 {
+		t.Errorf("Expected (0, false) for first Swap, got (%v, %v)", previous, loaded)
+	}
+
+	previous, loaded = csMap.Swap("key1", 2)
+	if !loaded || previous != 1 {
+		t.Errorf("Expected (1, true) for second Swap, got (%v, %v)", previous, loaded)
+	}
+
+	value, _ := csMap.Get("key1")
+	if value != 2 {
+		t.Errorf("Expected 'key1' to hold 2 after Swap, got %v", value)
+	}
+}
+
+func TestCSMap_CompareAndSwap(t *testing.T) {
+	csMap := NewCSMap[string, int](10)
+	csMap.Set("key1", 1)
+
+	if csMap.CompareAndSwap("key1", 2, 3) {
+		t.Errorf("Expected CompareAndSwap to fail when old does not match")
+	}
+
+	if !csMap.CompareAndSwap("key1", 1, 3) {
+		t.Errorf("Expected CompareAndSwap to succeed when old matches")
+	}
+
+	value, _ := csMap.Get("key1")
+	if value != 3 {
+		t.Errorf("Expected 'key1' to hold 3 after CompareAndSwap, got %v", value)
+	}
+}
+
+func TestCSMap_CompareAndDelete(t *testing.T) {
+	csMap := NewCSMap[string, int](10)
+	csMap.Set("key1", 1)
+
+	if csMap.CompareAndDelete("key1", 2) {
+		t.Errorf("Expected CompareAndDelete to fail when old does not match")
+	}
+
+	if !csMap.CompareAndDelete("key1", 1) {
+		t.Errorf("Expected CompareAndDelete to succeed when old matches")
+	}
+
+	if _, ok := csMap.Get("key1"); ok {
+		t.Errorf("Expected 'key1' to be deleted after CompareAndDelete")
+	}
+}
+
+func TestCSMap_CompareAndSwap_CrossShardCallback(t *testing.T) {
+	var csMap *CSMap[string, int]
+	// The equality function below re-enters the map on a different key while the shard
+	// lock for "a" is held. As long as "a" and "other" land on different shards, this
+	// must not deadlock since each shard has its own lock.
+	csMap = NewCSMapWithEq[string, int](4, func(x, y int) bool {
+		csMap.Set("other", x)
+		return x == y
+	})
+	csMap.Set("a", 1)
+
+	if !csMap.CompareAndSwap("a", 1, 2) {
+		t.Errorf("Expected CompareAndSwap to succeed")
+	}
+}
+
+func TestCSMap_EqualStringsShareShard(t *testing.T) {
+	csMap := NewCSMap[string, int](16)
+
+	// Build two distinct strings with identical contents but different backing arrays,
+	// so a hasher that reads the string header's data pointer (rather than its bytes)
+	// would send them to different shards.
+	a := string([]byte("same-contents"))
+	b := string([]byte("same-contents"))
+
+	if csMap.getShard(a) != csMap.getShard(b) {
+		t.Errorf("Expected equal strings %q and %q to map to the same shard", a, b)
+	}
+}
+
+func TestCSMap_NewCSMapWithHasher(t *testing.T) {
+	calls := 0
+	csMap := NewCSMapWithHasher[string, int](4, func(key string) uint64 {
+		calls++
+		return 0
+	})
+
+	csMap.Set("key1", 1)
+	csMap.Set("key2", 2)
+
+	if calls == 0 {
+		t.Errorf("Expected the custom hasher to be invoked")
+	}
+
+	value, ok := csMap.Get("key1")
+	if !ok || value != 1 {
+		t.Errorf("Expected value 1 for 'key1', got %v, ok: %v", value, ok)
+	}
+}
+
+func TestCSMap_EqualByteArraysShareShard(t *testing.T) {
+	csMap := NewCSMap[[16]byte, int](16)
+
+	a := [16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+	b := a
+
+	if csMap.getShard(a) != csMap.getShard(b) {
+		t.Errorf("Expected equal byte-array keys to map to the same shard")
+	}
+
+	csMap.Set(a, 42)
+	value, ok := csMap.Get(b)
+	if !ok || value != 42 {
+		t.Errorf("Expected value 42 for equal byte-array key, got %v, ok: %v", value, ok)
+	}
+}
+
+func TestCSMap_ShardCountRoundsUpToPowerOfTwo(t *testing.T) {
+	csMap := NewCSMap[string, int](10)
+	if csMap.length != 16 {
+		t.Errorf("Expected shard count 10 to round up to 16, got %d", csMap.length)
+	}
+}
+
+func TestCSMap_Len(t *testing.T) {
+	csMap := NewCSMap[string, int](10)
+
+	if got := csMap.Len(); got != 0 {
+		t.Errorf("Expected Len 0 for empty map, got %d", got)
+	}
+
+	csMap.Set("key1", 1)
+	csMap.Set("key2", 2)
+
+	if got := csMap.Len(); got != 2 {
+		t.Errorf("Expected Len 2, got %d", got)
+	}
+
+	csMap.Delete("key1")
+	if got := csMap.Len(); got != 1 {
+		t.Errorf("Expected Len 1 after delete, got %d", got)
+	}
+}
+
+func TestCSMap_KeysAndItems(t *testing.T) {
+	csMap := NewCSMap[string, int](10)
+	want := map[string]int{"key1": 1, "key2": 2, "key3": 3}
+	for k, v := range want {
+		csMap.Set(k, v)
+	}
+
+	keys := csMap.Keys()
+	if len(keys) != len(want) {
+		t.Fatalf("Expected %d keys, got %d", len(want), len(keys))
+	}
+	for _, k := range keys {
+		if _, ok := want[k]; !ok {
+			t.Errorf("Keys returned unexpected key %q", k)
+		}
+	}
+
+	items := csMap.Items()
+	if len(items) != len(want) {
+		t.Fatalf("Expected %d items, got %d", len(want), len(items))
+	}
+	for k, v := range want {
+		if items[k] != v {
+			t.Errorf("Expected items[%q] = %v, got %v", k, v, items[k])
+		}
+	}
+
+	// Items must be a snapshot, not a live view.
+	items["key1"] = 99
+	value, _ := csMap.Get("key1")
+	if value != 1 {
+		t.Errorf("Expected mutating the Items() snapshot to not affect the map")
+	}
+}
+
+func TestCSMap_Clear(t *testing.T) {
+	csMap := NewCSMap[string, int](10)
+	csMap.Set("key1", 1)
+	csMap.Set("key2", 2)
+
+	csMap.Clear()
+
+	if got := csMap.Len(); got != 0 {
+		t.Errorf("Expected Len 0 after Clear, got %d", got)
+	}
+	if _, ok := csMap.Get("key1"); ok {
+		t.Errorf("Expected 'key1' to be gone after Clear")
+	}
+}
+
+func TestCSMap_Upsert(t *testing.T) {
+	csMap := NewCSMap[string, int](10)
+
+	value := csMap.Upsert("key1", func(exists bool, old int) int {
+		if exists {
+			t.Errorf("Expected key1 to not exist on first Upsert")
+		}
+		return 1
+	})
+	if value != 1 {
+		t.Errorf("Expected Upsert to return 1, got %v", value)
+	}
+
+	value = csMap.Upsert("key1", func(exists bool, old int) int {
+		if !exists || old != 1 {
+			t.Errorf("Expected key1 to exist with value 1, got exists=%v old=%v", exists, old)
+		}
+		return old + 1
+	})
+	if value != 2 {
+		t.Errorf("Expected Upsert to return 2, got %v", value)
+	}
+
+	stored, _ := csMap.Get("key1")
+	if stored != 2 {
+		t.Errorf("Expected 'key1' to hold 2, got %v", stored)
+	}
+}
+
+func TestCSMap_RangeEarlyStop(t *testing.T) {
+	csMap := NewCSMap[int, int](10)
+	for i := 0; i < 10; i++ {
+		csMap.Set(i, i)
+	}
+
+	seen := 0
+	csMap.Range(func(k, v int) bool {
+		seen++
+		return seen < 3
+	})
+
+	if seen != 3 {
+		t.Errorf("Expected Range to stop after 3 callbacks, got %d", seen)
+	}
+}
+
+func TestCSMap_RangeReentrant(t *testing.T) {
+	csMap := NewCSMap[int, int](4)
+	for i := 0; i < 20; i++ {
+		csMap.Set(i, i)
+	}
+
+	// The callback mutates both the current shard's keys and foreign keys while a
+	// shard's entries are being delivered. Since Range copies each shard's entries
+	// before releasing its lock, this must not deadlock.
+	csMap.Range(func(k, v int) bool {
+		csMap.Set(k, v+100)
+		csMap.Set(k+1000, v)
+		csMap.Delete(k + 2000)
+		return true
+	})
+
+	if got := csMap.Len(); got < 20 {
+		t.Errorf("Expected Range mutations to be reflected, got Len %d", got)
+	}
+}
+
 // cpu: Intel(R) Core(TM) i7-7700 CPU @ 3.60GHz
 // BenchmarkCSMapSet
 // BenchmarkCSMapSet-8      6084016               176.5 ns/op            59 B/op            0 allocs/op
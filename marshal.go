@@ -0,0 +1,258 @@
+package csmap
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// MarshalJSON implements json.Marshaler. Keys are converted to JSON object keys via
+// keyToString: if K is string, the string is used directly; if K implements
+// fmt.Stringer, its String method is used; otherwise the key is formatted with
+// fmt.Sprint. The whole map is built in memory before marshaling - for very large maps,
+// prefer EncodeJSON.
+func (c *CSMap[K, V]) MarshalJSON() ([]byte, error) {
+	items := c.Items()
+	out := make(map[string]V, len(items))
+	for k, v := range items {
+		out[keyToString(k)] = v
+	}
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON implements json.Unmarshaler. JSON object keys are converted back to K
+// via stringToKey, which supports string and the built-in integer key types; any other
+// K returns an error, since there is no general way to invert fmt.Sprint.
+func (c *CSMap[K, V]) UnmarshalJSON(data []byte) error {
+	if c.shards == nil {
+		return fmt.Errorf("csmap: UnmarshalJSON: CSMap must be created with NewCSMap before unmarshaling")
+	}
+
+	var in map[string]V
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+
+	c.Clear()
+	for keyStr, v := range in {
+		key, err := stringToKey[K](keyStr)
+		if err != nil {
+			return err
+		}
+		c.Set(key, v)
+	}
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler using encoding/gob.
+func (c *CSMap[K, V]) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(c.Items()); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler using encoding/gob.
+func (c *CSMap[K, V]) UnmarshalBinary(data []byte) error {
+	if c.shards == nil {
+		return fmt.Errorf("csmap: UnmarshalBinary: CSMap must be created with NewCSMap before unmarshaling")
+	}
+
+	var items map[K]V
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&items); err != nil {
+		return err
+	}
+
+	c.Clear()
+	for k, v := range items {
+		c.Set(k, v)
+	}
+	return nil
+}
+
+// EncodeJSON streams the map to w as a single JSON object, one shard at a time under
+// that shard's RLock, without ever materializing the full map in memory. This is the
+// preferred way to serialize maps too large to comfortably copy via MarshalJSON.
+func (c *CSMap[K, V]) EncodeJSON(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	if _, err := bw.WriteString("{"); err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(bw)
+	first := true
+	for _, s := range c.shards {
+		s.mu.RLock()
+		entries := make([]keyValue[K, V], 0, len(s.m))
+		for k, v := range s.m {
+			entries = append(entries, keyValue[K, V]{key: k, value: v})
+		}
+		s.mu.RUnlock()
+
+		for _, e := range entries {
+			if !first {
+				if _, err := bw.WriteString(","); err != nil {
+					return err
+				}
+			}
+			first = false
+
+			keyBytes, err := json.Marshal(keyToString(e.key))
+			if err != nil {
+				return err
+			}
+			if _, err := bw.Write(keyBytes); err != nil {
+				return err
+			}
+			if _, err := bw.WriteString(":"); err != nil {
+				return err
+			}
+			if err := enc.Encode(e.value); err != nil {
+				return err
+			}
+		}
+	}
+
+	if _, err := bw.WriteString("}"); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// DecodeJSON reads a JSON object produced by EncodeJSON or MarshalJSON from r, setting
+// one entry at a time as it is decoded rather than unmarshaling the whole object first.
+func (c *CSMap[K, V]) DecodeJSON(r io.Reader) error {
+	if c.shards == nil {
+		return fmt.Errorf("csmap: DecodeJSON: CSMap must be created with NewCSMap before unmarshaling")
+	}
+
+	dec := json.NewDecoder(r)
+
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '{' {
+		return fmt.Errorf("csmap: DecodeJSON: expected '{', got %v", tok)
+	}
+
+	for dec.More() {
+		keyTok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		keyStr, ok := keyTok.(string)
+		if !ok {
+			return fmt.Errorf("csmap: DecodeJSON: expected object key, got %v", keyTok)
+		}
+		key, err := stringToKey[K](keyStr)
+		if err != nil {
+			return err
+		}
+
+		var value V
+		if err := dec.Decode(&value); err != nil {
+			return err
+		}
+		c.Set(key, value)
+	}
+
+	_, err = dec.Token() // consume the closing '}'
+	return err
+}
+
+// keyToString converts a map key to its JSON object key representation.
+func keyToString[K comparable](key K) string {
+	switch k := any(key).(type) {
+	case string:
+		return k
+	case fmt.Stringer:
+		return k.String()
+	default:
+		return fmt.Sprint(key)
+	}
+}
+
+// stringToKey converts a JSON object key back into K. It supports string and the
+// built-in integer types; any other K returns an error, since fmt.Sprint's formatting
+// can't be inverted in general.
+func stringToKey[K comparable](s string) (K, error) {
+	var zero K
+	switch any(zero).(type) {
+	case string:
+		return any(s).(K), nil
+	case int:
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return zero, err
+		}
+		return any(n).(K), nil
+	case int8:
+		n, err := strconv.ParseInt(s, 10, 8)
+		if err != nil {
+			return zero, err
+		}
+		return any(int8(n)).(K), nil
+	case int16:
+		n, err := strconv.ParseInt(s, 10, 16)
+		if err != nil {
+			return zero, err
+		}
+		return any(int16(n)).(K), nil
+	case int32:
+		n, err := strconv.ParseInt(s, 10, 32)
+		if err != nil {
+			return zero, err
+		}
+		return any(int32(n)).(K), nil
+	case int64:
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return zero, err
+		}
+		return any(n).(K), nil
+	case uint:
+		n, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return zero, err
+		}
+		return any(uint(n)).(K), nil
+	case uint8:
+		n, err := strconv.ParseUint(s, 10, 8)
+		if err != nil {
+			return zero, err
+		}
+		return any(uint8(n)).(K), nil
+	case uint16:
+		n, err := strconv.ParseUint(s, 10, 16)
+		if err != nil {
+			return zero, err
+		}
+		return any(uint16(n)).(K), nil
+	case uint32:
+		n, err := strconv.ParseUint(s, 10, 32)
+		if err != nil {
+			return zero, err
+		}
+		return any(uint32(n)).(K), nil
+	case uint64:
+		n, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return zero, err
+		}
+		return any(n).(K), nil
+	case uintptr:
+		n, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return zero, err
+		}
+		return any(uintptr(n)).(K), nil
+	default:
+		return zero, fmt.Errorf("csmap: cannot convert JSON object key %q into key type %T", s, zero)
+	}
+}
@@ -0,0 +1,180 @@
+package csmap
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestCSMap_JSONRoundTrip(t *testing.T) {
+	csMap := NewCSMap[string, int](10)
+	csMap.Set("key1", 1)
+	csMap.Set("key2", 2)
+
+	data, err := json.Marshal(csMap)
+	if err != nil {
+		t.Fatalf("MarshalJSON failed: %v", err)
+	}
+
+	decoded := NewCSMap[string, int](10)
+	if err := json.Unmarshal(data, decoded); err != nil {
+		t.Fatalf("UnmarshalJSON failed: %v", err)
+	}
+
+	if value, ok := decoded.Get("key1"); !ok || value != 1 {
+		t.Errorf("Expected 'key1' to be 1 after round-trip, got %v, ok: %v", value, ok)
+	}
+	if value, ok := decoded.Get("key2"); !ok || value != 2 {
+		t.Errorf("Expected 'key2' to be 2 after round-trip, got %v, ok: %v", value, ok)
+	}
+}
+
+func TestCSMap_JSONRoundTrip_IntKeys(t *testing.T) {
+	csMap := NewCSMap[int, string](10)
+	csMap.Set(1, "one")
+	csMap.Set(2, "two")
+
+	data, err := json.Marshal(csMap)
+	if err != nil {
+		t.Fatalf("MarshalJSON failed: %v", err)
+	}
+
+	decoded := NewCSMap[int, string](10)
+	if err := json.Unmarshal(data, decoded); err != nil {
+		t.Fatalf("UnmarshalJSON failed: %v", err)
+	}
+
+	if value, ok := decoded.Get(1); !ok || value != "one" {
+		t.Errorf("Expected 1 to be 'one' after round-trip, got %v, ok: %v", value, ok)
+	}
+}
+
+func TestCSMap_BinaryRoundTrip(t *testing.T) {
+	csMap := NewCSMap[string, int](10)
+	csMap.Set("key1", 1)
+	csMap.Set("key2", 2)
+
+	data, err := csMap.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary failed: %v", err)
+	}
+
+	decoded := NewCSMap[string, int](10)
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary failed: %v", err)
+	}
+
+	if value, ok := decoded.Get("key1"); !ok || value != 1 {
+		t.Errorf("Expected 'key1' to be 1 after round-trip, got %v, ok: %v", value, ok)
+	}
+	if value, ok := decoded.Get("key2"); !ok || value != 2 {
+		t.Errorf("Expected 'key2' to be 2 after round-trip, got %v, ok: %v", value, ok)
+	}
+}
+
+func TestCSMap_EncodeDecodeJSONRoundTrip(t *testing.T) {
+	csMap := NewCSMap[string, int](10)
+	for i := 0; i < 100; i++ {
+		csMap.Set(string(rune('a'+i%26))+string(rune('0'+i%10)), i)
+	}
+
+	var buf bytes.Buffer
+	if err := csMap.EncodeJSON(&buf); err != nil {
+		t.Fatalf("EncodeJSON failed: %v", err)
+	}
+
+	decoded := NewCSMap[string, int](10)
+	if err := decoded.DecodeJSON(&buf); err != nil {
+		t.Fatalf("DecodeJSON failed: %v", err)
+	}
+
+	wantKeys := csMap.Keys()
+	gotKeys := decoded.Keys()
+	sort.Strings(wantKeys)
+	sort.Strings(gotKeys)
+	if len(wantKeys) != len(gotKeys) {
+		t.Fatalf("Expected %d keys after round-trip, got %d", len(wantKeys), len(gotKeys))
+	}
+	for i := range wantKeys {
+		if wantKeys[i] != gotKeys[i] {
+			t.Errorf("Expected key %q at position %d, got %q", wantKeys[i], i, gotKeys[i])
+		}
+	}
+
+	for k, want := range csMap.Items() {
+		if got, ok := decoded.Get(k); !ok || got != want {
+			t.Errorf("Expected %q to be %v after round-trip, got %v, ok: %v", k, want, got, ok)
+		}
+	}
+}
+
+func TestCSMap_UnmarshalJSON_ZeroValue(t *testing.T) {
+	var csMap CSMap[string, int]
+	if err := json.Unmarshal([]byte(`{"key1":1}`), &csMap); err == nil {
+		t.Errorf("Expected UnmarshalJSON on a zero-value CSMap to return an error, not panic")
+	}
+}
+
+func TestCSMap_UnmarshalBinary_ZeroValue(t *testing.T) {
+	src := NewCSMap[string, int](10)
+	src.Set("key1", 1)
+	data, err := src.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary failed: %v", err)
+	}
+
+	var csMap CSMap[string, int]
+	if err := csMap.UnmarshalBinary(data); err == nil {
+		t.Errorf("Expected UnmarshalBinary on a zero-value CSMap to return an error, not panic")
+	}
+}
+
+func TestCSMap_DecodeJSON_ZeroValue(t *testing.T) {
+	var csMap CSMap[string, int]
+	if err := csMap.DecodeJSON(strings.NewReader(`{"key1":1}`)); err == nil {
+		t.Errorf("Expected DecodeJSON on a zero-value CSMap to return an error, not panic")
+	}
+}
+
+// marshalBenchEntries is large enough (1M) to show the allocation difference that
+// EncodeJSON's streaming design is meant to avoid; MarshalJSON has to materialize the
+// whole map (and a string-keyed copy of it) before it can call json.Marshal, while
+// EncodeJSON only ever holds one shard's entries at a time.
+const marshalBenchEntries = 1_000_000
+
+func BenchmarkCSMap_MarshalJSON(b *testing.B) {
+	if testing.Short() {
+		b.Skip("skipping 1M-entry benchmark in short mode")
+	}
+	csm := NewCSMap[int, int](32)
+	for i := 0; i < marshalBenchEntries; i++ {
+		csm.Set(i, i)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := json.Marshal(csm); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.ReportAllocs()
+}
+
+func BenchmarkCSMap_EncodeJSON(b *testing.B) {
+	if testing.Short() {
+		b.Skip("skipping 1M-entry benchmark in short mode")
+	}
+	csm := NewCSMap[int, int](32)
+	for i := 0; i < marshalBenchEntries; i++ {
+		csm.Set(i, i)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := csm.EncodeJSON(io.Discard); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.ReportAllocs()
+}
@@ -0,0 +1,266 @@
+package csmap
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// sweepInterval is how often the background sweeper visits a shard. Each tick advances
+// to the next shard in round-robin order, so a map with N shards gets a full sweep pass
+// roughly every N*sweepInterval.
+const sweepInterval = 100 * time.Millisecond
+
+// maxSweepScan bounds how many entries a single sweep of one shard will inspect, and
+// maxSweepDuration bounds how long it may run, whichever limit is hit first. Both exist
+// so a sweep of a large shard never blocks that shard's hot path for long.
+const (
+	maxSweepScan     = 1024
+	maxSweepDuration = 2 * time.Millisecond
+)
+
+// ttlEntry is the value stored in a TTLCSMap shard: the user value plus its expiration
+// time as a Unix nanosecond timestamp, or 0 if the entry never expires.
+type ttlEntry[V any] struct {
+	v        V
+	expireAt int64
+}
+
+// expired reports whether the entry's expiration time has passed as of now.
+func (e ttlEntry[V]) expired(now int64) bool {
+	return e.expireAt != 0 && e.expireAt <= now
+}
+
+// ttlShard is a single shard of a TTLCSMap: its entries, guarded by an RWMutex, plus
+// per-shard hit/miss/expired counters, which are atomics updated without that lock.
+type ttlShard[K comparable, V any] struct {
+	mu      sync.RWMutex
+	m       map[K]ttlEntry[V]
+	hits    atomic.Uint64
+	misses  atomic.Uint64
+	expired atomic.Uint64
+}
+
+// TTLCSMap is a sharded concurrent hashmap where every entry carries an expiration
+// time. Get treats an expired entry as missing and deletes it lazily (under a write
+// lock upgrade); a single background goroutine also sweeps the shards in round-robin
+// order so that entries which are set and never read again are still eventually
+// reclaimed. Create one with NewCSMapWithTTL and call Close when done with it to stop
+// the sweeper goroutine.
+type TTLCSMap[K comparable, V any] struct {
+	shards     []*ttlShard[K, V]
+	length     int // Number of shards, always a power of two
+	mask       uint64
+	hasher     func(K) uint64
+	defaultTTL time.Duration
+
+	closeOnce sync.Once
+	closeCh   chan struct{}
+}
+
+// ShardStats reports the hit/miss/expired counters for a single shard, as returned by
+// TTLCSMap.Stats.
+type ShardStats struct {
+	Hits    uint64
+	Misses  uint64
+	Expired uint64
+}
+
+// NewCSMapWithTTL creates a new TTLCSMap with the specified number of shards and starts
+// its background sweeper goroutine. defaultTTL is the expiration used by Set, which
+// takes no explicit ttl (SetEx and Touch always take one); a defaultTTL of 0 means entries set via Set never
+// expire on their own (they can still be given an explicit ttl via SetEx).
+func NewCSMapWithTTL[K comparable, V any](shards int, defaultTTL time.Duration) *TTLCSMap[K, V] {
+	if shards < 1 {
+		shards = 1
+	}
+	shards = nextPowerOfTwo(shards)
+
+	shardList := make([]*ttlShard[K, V], shards)
+	for i := 0; i < shards; i++ {
+		shardList[i] = &ttlShard[K, V]{
+			m: make(map[K]ttlEntry[V]),
+		}
+	}
+
+	t := &TTLCSMap[K, V]{
+		shards:     shardList,
+		length:     shards,
+		mask:       uint64(shards - 1),
+		hasher:     defaultHasher[K],
+		defaultTTL: defaultTTL,
+		closeCh:    make(chan struct{}),
+	}
+	go t.sweep()
+	return t
+}
+
+// getShard returns the shard that should contain the given key.
+func (t *TTLCSMap[K, V]) getShard(key K) *ttlShard[K, V] {
+	return t.shards[t.hasher(key)&t.mask]
+}
+
+// Set adds or updates the value for a given key using the map's defaultTTL.
+func (t *TTLCSMap[K, V]) Set(key K, value V) {
+	t.SetEx(key, value, t.defaultTTL)
+}
+
+// SetEx adds or updates the value for a given key with an explicit ttl. A ttl of 0 (or
+// negative) means the entry never expires on its own.
+func (t *TTLCSMap[K, V]) SetEx(key K, value V, ttl time.Duration) {
+	s := t.getShard(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.m[key] = ttlEntry[V]{v: value, expireAt: expireAtFor(ttl)}
+}
+
+// expireAtFor converts a ttl into an absolute Unix nanosecond expiration time, or 0 if
+// ttl means "never expires".
+func expireAtFor(ttl time.Duration) int64 {
+	if ttl <= 0 {
+		return 0
+	}
+	return time.Now().Add(ttl).UnixNano()
+}
+
+// Get retrieves the value associated with the given key. An expired entry is treated as
+// missing: Get returns ok=false and deletes the entry under a write lock upgrade.
+func (t *TTLCSMap[K, V]) Get(key K) (V, bool) {
+	s := t.getShard(key)
+
+	s.mu.RLock()
+	e, ok := s.m[key]
+	s.mu.RUnlock()
+
+	if !ok {
+		s.misses.Add(1)
+		var zero V
+		return zero, false
+	}
+
+	if e.expired(time.Now().UnixNano()) {
+		s.mu.Lock()
+		// Re-check under the write lock: the entry may have been refreshed by a
+		// concurrent Set/Touch between the RUnlock above and this Lock.
+		if cur, ok := s.m[key]; ok && cur.expireAt == e.expireAt {
+			delete(s.m, key)
+		}
+		s.mu.Unlock()
+		s.expired.Add(1)
+		var zero V
+		return zero, false
+	}
+
+	s.hits.Add(1)
+	return e.v, true
+}
+
+// Delete removes the key and its associated value from the map.
+func (t *TTLCSMap[K, V]) Delete(key K) {
+	s := t.getShard(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.m, key)
+}
+
+// TTL returns the remaining time until key expires, and whether key is currently
+// present (and not expired). An entry with no expiration (set with ttl <= 0) reports a
+// remaining time of 0.
+func (t *TTLCSMap[K, V]) TTL(key K) (time.Duration, bool) {
+	s := t.getShard(key)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	e, ok := s.m[key]
+	if !ok {
+		return 0, false
+	}
+	now := time.Now().UnixNano()
+	if e.expired(now) {
+		return 0, false
+	}
+	if e.expireAt == 0 {
+		return 0, true
+	}
+	return time.Duration(e.expireAt - now), true
+}
+
+// Touch refreshes the expiration of an existing key to ttl from now, without changing
+// its value. It reports whether the key was present (and not expired) to refresh.
+func (t *TTLCSMap[K, V]) Touch(key K, ttl time.Duration) bool {
+	s := t.getShard(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.m[key]
+	if !ok {
+		return false
+	}
+	if e.expired(time.Now().UnixNano()) {
+		delete(s.m, key)
+		return false
+	}
+
+	s.m[key] = ttlEntry[V]{v: e.v, expireAt: expireAtFor(ttl)}
+	return true
+}
+
+// Stats returns the hit/miss/expired counters for each shard, in shard order.
+func (t *TTLCSMap[K, V]) Stats() []ShardStats {
+	stats := make([]ShardStats, len(t.shards))
+	for i, s := range t.shards {
+		stats[i] = ShardStats{
+			Hits:    s.hits.Load(),
+			Misses:  s.misses.Load(),
+			Expired: s.expired.Load(),
+		}
+	}
+	return stats
+}
+
+// Close stops the background sweeper goroutine. It is safe to call more than once.
+func (t *TTLCSMap[K, V]) Close() {
+	t.closeOnce.Do(func() {
+		close(t.closeCh)
+	})
+}
+
+// sweep runs on its own goroutine for the lifetime of the map, visiting one shard per
+// tick in round-robin order until Close is called.
+func (t *TTLCSMap[K, V]) sweep() {
+	ticker := time.NewTicker(sweepInterval)
+	defer ticker.Stop()
+
+	idx := 0
+	for {
+		select {
+		case <-t.closeCh:
+			return
+		case <-ticker.C:
+			t.sweepShard(t.shards[idx])
+			idx = (idx + 1) % len(t.shards)
+		}
+	}
+}
+
+// sweepShard removes expired entries from a single shard, scanning at most
+// maxSweepScan entries or for at most maxSweepDuration, whichever comes first, so a
+// large shard's sweep never blocks that shard's hot path for long.
+func (t *TTLCSMap[K, V]) sweepShard(s *ttlShard[K, V]) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	deadline := time.Now().Add(maxSweepDuration)
+	now := time.Now().UnixNano()
+	scanned := 0
+	for k, e := range s.m {
+		if scanned >= maxSweepScan || time.Now().After(deadline) {
+			break
+		}
+		scanned++
+		if e.expired(now) {
+			delete(s.m, k)
+			s.expired.Add(1)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package csmap
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTTLCSMap_SetAndGet(t *testing.T) {
+	csMap := NewCSMapWithTTL[string, int](10, time.Minute)
+	defer csMap.Close()
+
+	csMap.Set("key1", 1)
+
+	value, ok := csMap.Get("key1")
+	if !ok || value != 1 {
+		t.Errorf("Expected value 1 for 'key1', got %v, ok: %v", value, ok)
+	}
+}
+
+func TestTTLCSMap_LazyExpiry(t *testing.T) {
+	csMap := NewCSMapWithTTL[string, int](10, 0)
+	defer csMap.Close()
+
+	csMap.SetEx("key1", 1, 10*time.Millisecond)
+
+	if value, ok := csMap.Get("key1"); !ok || value != 1 {
+		t.Errorf("Expected 'key1' to be present before expiry, got %v, ok: %v", value, ok)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+
+	if _, ok := csMap.Get("key1"); ok {
+		t.Errorf("Expected 'key1' to be expired")
+	}
+}
+
+func TestTTLEntry_ExpiredAtBoundary(t *testing.T) {
+	now := time.Now().UnixNano()
+	e := ttlEntry[int]{v: 1, expireAt: now}
+
+	// expireAt == now must already count as expired: the entry's deadline has been
+	// reached, not merely approached.
+	if !e.expired(now) {
+		t.Errorf("Expected an entry with expireAt == now to be expired")
+	}
+	// One nanosecond earlier, it must not yet be expired.
+	if e.expired(now - 1) {
+		t.Errorf("Expected an entry to not be expired one nanosecond before its expireAt")
+	}
+	// An entry with no expiry (expireAt == 0) is never expired.
+	if (ttlEntry[int]{v: 1, expireAt: 0}).expired(now) {
+		t.Errorf("Expected an entry with expireAt == 0 to never be expired")
+	}
+}
+
+func TestTTLCSMap_GetAtExactExpiryBoundary(t *testing.T) {
+	csMap := NewCSMapWithTTL[string, int](4, 0)
+	defer csMap.Close()
+
+	// Place an entry directly, with expireAt pinned to "now", instead of sleeping past
+	// a short ttl. Get reads the clock again, so it sees a time at or just past
+	// expireAt; the exact nanosecond case is checked in TestTTLEntry_ExpiredAtBoundary.
+	s := csMap.getShard("key1")
+	s.mu.Lock()
+	s.m["key1"] = ttlEntry[int]{v: 1, expireAt: time.Now().UnixNano()}
+	s.mu.Unlock()
+
+	if _, ok := csMap.Get("key1"); ok {
+		t.Errorf("Expected Get to report ok=false for an entry at its exact expiry boundary")
+	}
+}
+
+func TestTTLCSMap_NoExpiry(t *testing.T) {
+	csMap := NewCSMapWithTTL[string, int](10, 0)
+	defer csMap.Close()
+
+	csMap.SetEx("key1", 1, 0)
+
+	ttl, ok := csMap.TTL("key1")
+	if !ok || ttl != 0 {
+		t.Errorf("Expected (0, true) for a key with no expiry, got (%v, %v)", ttl, ok)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+
+	if _, ok := csMap.Get("key1"); !ok {
+		t.Errorf("Expected 'key1' with no expiry to still be present")
+	}
+}
+
+func TestTTLCSMap_TTL(t *testing.T) {
+	csMap := NewCSMapWithTTL[string, int](10, 0)
+	defer csMap.Close()
+
+	csMap.SetEx("key1", 1, 50*time.Millisecond)
+
+	ttl, ok := csMap.TTL("key1")
+	if !ok || ttl <= 0 || ttl > 50*time.Millisecond {
+		t.Errorf("Expected a positive remaining TTL <= 50ms, got %v, ok: %v", ttl, ok)
+	}
+
+	if _, ok := csMap.TTL("missing"); ok {
+		t.Errorf("Expected TTL on a missing key to report ok=false")
+	}
+}
+
+func TestTTLCSMap_Touch(t *testing.T) {
+	csMap := NewCSMapWithTTL[string, int](10, 0)
+	defer csMap.Close()
+
+	if csMap.Touch("key1", time.Minute) {
+		t.Errorf("Expected Touch on a missing key to return false")
+	}
+
+	csMap.SetEx("key1", 1, 10*time.Millisecond)
+	if !csMap.Touch("key1", time.Minute) {
+		t.Errorf("Expected Touch on an existing key to return true")
+	}
+
+	time.Sleep(20 * time.Millisecond)
+
+	if value, ok := csMap.Get("key1"); !ok || value != 1 {
+		t.Errorf("Expected 'key1' to survive past its original ttl after Touch, got %v, ok: %v", value, ok)
+	}
+}
+
+func TestTTLCSMap_Delete(t *testing.T) {
+	csMap := NewCSMapWithTTL[string, int](10, time.Minute)
+	defer csMap.Close()
+
+	csMap.Set("key1", 1)
+	csMap.Delete("key1")
+
+	if _, ok := csMap.Get("key1"); ok {
+		t.Errorf("Expected 'key1' to be deleted")
+	}
+}
+
+func TestTTLCSMap_Stats(t *testing.T) {
+	csMap := NewCSMapWithTTL[string, int](4, 0)
+	defer csMap.Close()
+
+	csMap.SetEx("key1", 1, 10*time.Millisecond)
+	csMap.Get("key1")
+	csMap.Get("missing")
+	time.Sleep(20 * time.Millisecond)
+	csMap.Get("key1")
+
+	var hits, misses, expired uint64
+	for _, s := range csMap.Stats() {
+		hits += s.Hits
+		misses += s.Misses
+		expired += s.Expired
+	}
+
+	if hits != 1 {
+		t.Errorf("Expected 1 hit across all shards, got %d", hits)
+	}
+	if misses != 1 {
+		t.Errorf("Expected 1 miss across all shards, got %d", misses)
+	}
+	if expired != 1 {
+		t.Errorf("Expected 1 expired across all shards, got %d", expired)
+	}
+}
+
+func TestTTLCSMap_SweeperReclaimsUnreadEntries(t *testing.T) {
+	csMap := NewCSMapWithTTL[string, int](4, 0)
+	defer csMap.Close()
+
+	csMap.SetEx("key1", 1, 10*time.Millisecond)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		s := csMap.getShard("key1")
+		s.mu.RLock()
+		_, stillPresent := s.m["key1"]
+		s.mu.RUnlock()
+		if !stillPresent {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Errorf("Expected the sweeper to reclaim an expired entry that is never read again")
+}